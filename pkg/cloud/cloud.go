@@ -0,0 +1,114 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cloud
+
+import (
+	"context"
+	"errors"
+
+	"github.com/IBM-Cloud/power-go-client/clients/instance"
+	"github.com/IBM-Cloud/power-go-client/ibmpisession"
+)
+
+const (
+	// DefaultVolumeSize is used when no capacity range is provided in CreateVolumeRequest
+	DefaultVolumeSize int64 = 10 * 1024 * 1024 * 1024
+)
+
+var (
+	// ErrNotFound is returned when a resource does not exist
+	ErrNotFound = errors.New("resource was not found")
+	// ErrAlreadyExists is returned when a resource already exists
+	ErrAlreadyExists = errors.New("resource already exists")
+	// ErrIdempotentParameterMismatch is returned when a resource already exists with different parameters than requested
+	ErrIdempotentParameterMismatch = errors.New("resource exists with a different configuration")
+	// ErrVolumeInUse is returned when a volume is still attached to an instance
+	ErrVolumeInUse = errors.New("volume is still attached to an instance")
+)
+
+// Disk represents a PowerVS volume
+type Disk struct {
+	VolumeID    string
+	WWN         string
+	CapacityGiB int64
+	// Shareable indicates the volume was created with Shareable: true and can be attached
+	// to more than one PVM instance at a time.
+	Shareable bool
+	// AttachedNodes lists the PVM instance IDs the volume is currently attached to.
+	AttachedNodes []string
+}
+
+// DiskOptions represents parameters to create a new volume
+type DiskOptions struct {
+	CapacityBytes int64
+	VolumeType    string
+	// Shareable marks the volume for multi-node attachment (PowerVS "shared" volume)
+	Shareable bool
+	// SnapshotID names a snapshot to create the volume from. The PowerVS-backed CreateDisk
+	// does not create volumes from snapshots: it returns an error when SnapshotID is set.
+	SnapshotID string
+}
+
+// PVMInstance represents a PowerVS compute instance
+type PVMInstance struct {
+	ID string
+}
+
+// Cloud abstracts the PowerVS APIs used by the driver so they can be faked out in tests.
+// Every method takes ctx so long-running PowerVS polling loops (attach/detach/snapshot
+// state waits) can honor the gRPC deadline set by the calling CSI sidecar.
+type Cloud interface {
+	CreateDisk(ctx context.Context, volumeName string, diskOptions *DiskOptions) (*Disk, error)
+	DeleteDisk(ctx context.Context, volumeID string) (bool, error)
+	AttachDisk(ctx context.Context, volumeID, nodeID string) error
+	DetachDisk(ctx context.Context, volumeID, nodeID string) error
+	ResizeDisk(ctx context.Context, volumeID string, newSizeBytes int64) (int32, error)
+	GetDiskByID(ctx context.Context, volumeID string) (*Disk, error)
+	ListDisks(ctx context.Context, maxEntries int32, startingToken string) ([]*Disk, string, error)
+	IsAttached(ctx context.Context, volumeID, nodeID string) (bool, error)
+	GetPVMInstanceByID(ctx context.Context, instanceID string) (*PVMInstance, error)
+
+	CreateSnapshot(ctx context.Context, volumeID, name string) (*Snapshot, error)
+	DeleteSnapshot(ctx context.Context, snapshotID string) error
+	ListSnapshots(ctx context.Context, volumeID string, maxEntries int32, startingToken string) ([]*Snapshot, string, error)
+	GetSnapshotByName(ctx context.Context, name string) (*Snapshot, error)
+}
+
+// cloud implements the Cloud interface against the IBM PowerVS APIs
+type cloud struct {
+	cloudInstanceID string
+	session         *ibmpisession.IBMPISession
+	volumeClient    *instance.IBMPIVolumeClient
+	instanceClient  *instance.IBMPIInstanceClient
+	snapshotClient  *instance.IBMPISnapshotClient
+}
+
+// NewPowerVSCloud returns a Cloud backed by the PowerVS service instance identified by cloudInstanceID
+func NewPowerVSCloud(cloudInstanceID string, debug bool) (Cloud, error) {
+	session, err := ibmpisession.NewIBMPISessionFromEnv(debug)
+	if err != nil {
+		return nil, err
+	}
+
+	return &cloud{
+		cloudInstanceID: cloudInstanceID,
+		session:         session,
+		volumeClient:    instance.NewIBMPIVolumeClient(session, cloudInstanceID),
+		instanceClient:  instance.NewIBMPIInstanceClient(session, cloudInstanceID),
+		snapshotClient:  instance.NewIBMPISnapshotClient(session, cloudInstanceID),
+	}, nil
+}
@@ -0,0 +1,220 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cloud
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/IBM-Cloud/power-go-client/power/models"
+	"k8s.io/klog/v2"
+)
+
+const (
+	// DiskStatusAvailable is the state PowerVS reports once a volume is ready to be attached
+	DiskStatusAvailable = "available"
+
+	gibibyte = 1024 * 1024 * 1024
+
+	diskPollInterval = 5 * time.Second
+	diskPollTimeout  = 5 * time.Minute
+)
+
+// CreateDisk creates a new volume named volumeName with the given options and waits for it
+// to become available. Creating a volume directly from a snapshot is not supported by the
+// PowerVS volume API, so diskOptions.SnapshotID is rejected rather than silently ignored.
+func (c *cloud) CreateDisk(ctx context.Context, volumeName string, diskOptions *DiskOptions) (*Disk, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	if diskOptions.SnapshotID != "" {
+		return nil, fmt.Errorf("creating volume %q from snapshot %q is not supported: PowerVS volumes cannot be created from a snapshot directly", volumeName, diskOptions.SnapshotID)
+	}
+
+	sizeGiB := float64(diskOptions.CapacityBytes) / gibibyte
+	shareable := diskOptions.Shareable
+	body := &models.CreateDataVolume{
+		Name:      &volumeName,
+		Size:      &sizeGiB,
+		Shareable: &shareable,
+		DiskType:  diskOptions.VolumeType,
+	}
+
+	resp, err := c.volumeClient.CreateVolume(body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create volume %q: %v", volumeName, err)
+	}
+
+	return c.waitForDiskState(ctx, diskFromPower(resp).VolumeID, DiskStatusAvailable)
+}
+
+// DeleteDisk deletes the volume identified by volumeID. It is a no-op if the volume has
+// already been removed.
+func (c *cloud) DeleteDisk(ctx context.Context, volumeID string) (bool, error) {
+	if err := ctx.Err(); err != nil {
+		return false, err
+	}
+
+	if err := c.volumeClient.DeleteVolume(volumeID); err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			return true, nil
+		}
+		if strings.Contains(err.Error(), "in use") {
+			return false, ErrVolumeInUse
+		}
+		return false, fmt.Errorf("failed to delete volume %q: %v", volumeID, err)
+	}
+	return true, nil
+}
+
+// AttachDisk attaches volumeID to the PVM instance nodeID and waits for PowerVS to report
+// the volume as attached before returning, so the gRPC deadline set by the calling CSI
+// sidecar bounds the whole operation rather than just the initial API call.
+func (c *cloud) AttachDisk(ctx context.Context, volumeID, nodeID string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	if err := c.volumeClient.Attach(nodeID, volumeID); err != nil {
+		if strings.Contains(err.Error(), "already attached") {
+			return ErrAlreadyExists
+		}
+		return fmt.Errorf("failed to attach volume %q to instance %q: %v", volumeID, nodeID, err)
+	}
+
+	err := waitUntil(ctx, diskPollInterval, diskPollTimeout, func() (bool, error) {
+		_, err := c.volumeClient.CheckVolumeAttach(nodeID, volumeID)
+		if err != nil {
+			klog.V(5).Infof("AttachDisk: waiting for volume %s to attach to instance %s", volumeID, nodeID)
+			return false, nil
+		}
+		return true, nil
+	})
+	if err != nil {
+		return fmt.Errorf("volume %q did not attach to instance %q: %w", volumeID, nodeID, err)
+	}
+	return nil
+}
+
+// DetachDisk detaches volumeID from the PVM instance nodeID and waits for PowerVS to report
+// the volume as detached before returning.
+func (c *cloud) DetachDisk(ctx context.Context, volumeID, nodeID string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	if err := c.volumeClient.Detach(nodeID, volumeID); err != nil {
+		return fmt.Errorf("failed to detach volume %q from instance %q: %v", volumeID, nodeID, err)
+	}
+
+	err := waitUntil(ctx, diskPollInterval, diskPollTimeout, func() (bool, error) {
+		if _, err := c.volumeClient.CheckVolumeAttach(nodeID, volumeID); err != nil {
+			return true, nil
+		}
+		klog.V(5).Infof("DetachDisk: waiting for volume %s to detach from instance %s", volumeID, nodeID)
+		return false, nil
+	})
+	if err != nil {
+		return fmt.Errorf("volume %q did not detach from instance %q: %w", volumeID, nodeID, err)
+	}
+	return nil
+}
+
+// ResizeDisk grows volumeID to newSizeBytes and returns the resulting size in GiB.
+func (c *cloud) ResizeDisk(ctx context.Context, volumeID string, newSizeBytes int64) (int32, error) {
+	if err := ctx.Err(); err != nil {
+		return 0, err
+	}
+
+	sizeGiB := float64(newSizeBytes) / gibibyte
+	resp, err := c.volumeClient.UpdateVolume(volumeID, &models.UpdateVolume{Size: sizeGiB})
+	if err != nil {
+		return 0, fmt.Errorf("failed to resize volume %q: %v", volumeID, err)
+	}
+	return int32(diskFromPower(resp).CapacityGiB), nil
+}
+
+// GetDiskByID returns the volume identified by volumeID, or ErrNotFound if it does not exist.
+func (c *cloud) GetDiskByID(ctx context.Context, volumeID string) (*Disk, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	resp, err := c.volumeClient.Get(volumeID)
+	if err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			return nil, ErrNotFound
+		}
+		return nil, fmt.Errorf("failed to get volume %q: %v", volumeID, err)
+	}
+	return diskFromPower(resp), nil
+}
+
+// IsAttached reports whether volumeID is currently attached to the PVM instance nodeID.
+func (c *cloud) IsAttached(ctx context.Context, volumeID, nodeID string) (bool, error) {
+	if err := ctx.Err(); err != nil {
+		return false, err
+	}
+
+	if _, err := c.volumeClient.CheckVolumeAttach(nodeID, volumeID); err != nil {
+		return false, nil
+	}
+	return true, nil
+}
+
+// GetPVMInstanceByID returns the PVM instance identified by instanceID, or ErrNotFound if
+// it does not exist.
+func (c *cloud) GetPVMInstanceByID(ctx context.Context, instanceID string) (*PVMInstance, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	resp, err := c.instanceClient.Get(instanceID)
+	if err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			return nil, ErrNotFound
+		}
+		return nil, fmt.Errorf("failed to get instance %q: %v", instanceID, err)
+	}
+	id := instanceID
+	if resp.PvmInstanceID != nil {
+		id = *resp.PvmInstanceID
+	}
+	return &PVMInstance{ID: id}, nil
+}
+
+// waitForDiskState polls until volumeID reaches the requested state, diskPollTimeout elapses,
+// or ctx is done - whichever comes first.
+func (c *cloud) waitForDiskState(ctx context.Context, volumeID, state string) (*Disk, error) {
+	var disk *Disk
+	err := waitUntil(ctx, diskPollInterval, diskPollTimeout, func() (bool, error) {
+		resp, err := c.volumeClient.Get(volumeID)
+		if err != nil {
+			return false, err
+		}
+		disk = diskFromPower(resp)
+		klog.V(5).Infof("waitForDiskState: volume %s is in state %q, waiting for %q", volumeID, resp.State, state)
+		return resp.State == state, nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("volume %q did not reach state %q: %w", volumeID, state, err)
+	}
+	return disk, nil
+}
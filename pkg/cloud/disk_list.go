@@ -0,0 +1,71 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cloud
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/IBM-Cloud/power-go-client/power/models"
+)
+
+// ListDisks lists the volumes owned by the service instance. startingToken/the returned
+// nextToken are opaque, base64-encoded offsets into the result set, matching the pagination
+// scheme used by ListSnapshots.
+func (c *cloud) ListDisks(ctx context.Context, maxEntries int32, startingToken string) ([]*Disk, string, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, "", err
+	}
+
+	offset, err := decodePageToken(startingToken)
+	if err != nil {
+		return nil, "", err
+	}
+
+	resp, err := c.volumeClient.GetAll()
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to list volumes: %v", err)
+	}
+
+	disks := make([]*Disk, 0, len(resp.Volumes))
+	for _, v := range resp.Volumes {
+		disks = append(disks, diskFromPower(v))
+	}
+
+	if offset >= len(disks) {
+		return nil, "", nil
+	}
+
+	end := len(disks)
+	nextToken := ""
+	if maxEntries > 0 && offset+int(maxEntries) < end {
+		end = offset + int(maxEntries)
+		nextToken = encodePageToken(end)
+	}
+
+	return disks[offset:end], nextToken, nil
+}
+
+func diskFromPower(v *models.Volume) *Disk {
+	return &Disk{
+		VolumeID:      v.VolumeID,
+		WWN:           v.Wwn,
+		CapacityGiB:   int64(v.Size),
+		Shareable:     v.Shareable,
+		AttachedNodes: v.AttachedPvmInstances,
+	}
+}
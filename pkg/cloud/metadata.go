@@ -85,15 +85,55 @@ func TokenizeProviderID(providerID string) (*Metadata, error) {
 	}, nil
 }
 
-// Get New Metadata Service
-func NewMetadataService(k8sAPIClient KubernetesAPIClient) (MetadataService, error) {
-	klog.Infof("retrieving instance data from kubernetes api")
-	clientset, err := k8sAPIClient()
-	if err != nil {
-		klog.Warningf("error creating kubernetes api client: %v", err)
-	} else {
-		klog.Infof("kubernetes api is available")
-		return KubernetesAPIInstanceInfo(clientset)
+// MetadataSource selects which backend NewMetadataService uses to discover instance identity
+type MetadataSource string
+
+const (
+	// MetadataSourceAuto tries the Kubernetes API first, then falls back to local discovery
+	MetadataSourceAuto MetadataSource = "auto"
+	// MetadataSourceKubernetes forces discovery via the Kubernetes API only
+	MetadataSourceKubernetes MetadataSource = "kubernetes"
+	// MetadataSourceLocal forces local cloud-init/SMBIOS discovery only
+	MetadataSourceLocal MetadataSource = "local"
+)
+
+// NewMetadataService returns a MetadataService appropriate for source. With
+// MetadataSourceAuto (the default) it tries the Kubernetes API first - as the node's
+// ProviderID is the most reliable source once the node object exists - and falls back to
+// local discovery for node plugins that start before then, or on non-Kubernetes hosts,
+// mirroring the layered EC2 IMDS/Kubernetes fallback used by the AWS EBS CSI driver.
+func NewMetadataService(k8sAPIClient KubernetesAPIClient, source MetadataSource) (MetadataService, error) {
+	if source == "" {
+		source = MetadataSourceAuto
 	}
-	return nil, fmt.Errorf("error getting instance data from ec2 metadata or kubernetes api")
+	var errs []string
+
+	if source == MetadataSourceAuto || source == MetadataSourceKubernetes {
+		klog.Infof("retrieving instance data from kubernetes api")
+		clientset, err := k8sAPIClient()
+		if err != nil {
+			errs = append(errs, fmt.Sprintf("kubernetes api: %v", err))
+		} else {
+			klog.Infof("kubernetes api is available")
+			metadata, err := KubernetesAPIInstanceInfo(clientset)
+			if err == nil {
+				return metadata, nil
+			}
+			errs = append(errs, fmt.Sprintf("kubernetes api: %v", err))
+		}
+		if source == MetadataSourceKubernetes {
+			return nil, fmt.Errorf("error getting instance data from kubernetes api: %s", errs[0])
+		}
+	}
+
+	if source == MetadataSourceAuto || source == MetadataSourceLocal {
+		klog.Infof("retrieving instance data from local cloud-init/SMBIOS metadata")
+		metadata, err := NewLocalMetadataService()
+		if err == nil {
+			return metadata, nil
+		}
+		errs = append(errs, fmt.Sprintf("local metadata: %v", err))
+	}
+
+	return nil, fmt.Errorf("error getting instance data, tried: %s", strings.Join(errs, "; "))
 }
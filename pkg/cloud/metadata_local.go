@@ -0,0 +1,90 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cloud
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+)
+
+const (
+	cloudInitInstanceDataPath = "/run/cloud-init/instance-data.json"
+	dmiProductUUIDPath        = "/sys/class/dmi/id/product_uuid"
+)
+
+// cloudInitInstanceData is the subset of cloud-init's instance-data.json populated on
+// PowerVS instances that the driver cares about.
+type cloudInitInstanceData struct {
+	V1 struct {
+		Region           string `json:"region"`
+		AvailabilityZone string `json:"availability_zone"`
+		CloudInstanceID  string `json:"instance_id"`
+	} `json:"v1"`
+}
+
+// NewLocalMetadataService builds a Metadata by parsing PowerVS cloud-init metadata and the
+// SMBIOS/DMI product UUID directly on the host, for use when the Kubernetes API is
+// unavailable (the node plugin starting before the node object exists, or bare test rigs
+// and sanity suites that aren't running against a Kubernetes cluster at all).
+func NewLocalMetadataService() (MetadataService, error) {
+	pvmInstanceID, err := readProductUUID(dmiProductUUIDPath)
+	if err != nil {
+		return nil, fmt.Errorf("could not read pvm instance id from SMBIOS: %v", err)
+	}
+
+	data, err := readCloudInitInstanceData(cloudInitInstanceDataPath)
+	if err != nil {
+		return nil, fmt.Errorf("could not read cloud-init instance data: %v", err)
+	}
+
+	return &Metadata{
+		region:          data.V1.Region,
+		zone:            data.V1.AvailabilityZone,
+		cloudInstanceId: data.V1.CloudInstanceID,
+		pvmInstanceId:   pvmInstanceID,
+	}, nil
+}
+
+func readCloudInitInstanceData(path string) (*cloudInitInstanceData, error) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var data cloudInitInstanceData
+	if err := json.Unmarshal(raw, &data); err != nil {
+		return nil, fmt.Errorf("invalid cloud-init instance data in %q: %v", path, err)
+	}
+	if data.V1.Region == "" || data.V1.CloudInstanceID == "" {
+		return nil, fmt.Errorf("incomplete cloud-init instance data in %q", path)
+	}
+	return &data, nil
+}
+
+func readProductUUID(path string) (string, error) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+	uuid := strings.TrimSpace(string(raw))
+	if uuid == "" {
+		return "", fmt.Errorf("empty product UUID in %q", path)
+	}
+	return uuid, nil
+}
@@ -0,0 +1,197 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cloud
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/IBM-Cloud/power-go-client/power/models"
+	"k8s.io/klog/v2"
+)
+
+const (
+	// SnapshotStatusAvailable is the state PowerVS reports once a snapshot is ready to be used
+	SnapshotStatusAvailable = "available"
+
+	snapshotPollInterval = 5 * time.Second
+	snapshotPollTimeout  = 5 * time.Minute
+)
+
+// Snapshot represents a PowerVS volume snapshot
+type Snapshot struct {
+	SnapshotID     string
+	SourceVolumeID string
+	SizeGiB        int64
+	CreationTime   time.Time
+	ReadyToUse     bool
+}
+
+// CreateSnapshot creates a snapshot of volumeID, deduping by name so repeated calls from
+// external-snapshotter for the same snapshot are idempotent.
+func (c *cloud) CreateSnapshot(ctx context.Context, volumeID, name string) (*Snapshot, error) {
+	existing, err := c.GetSnapshotByName(ctx, name)
+	if err != nil && err != ErrNotFound {
+		return nil, err
+	}
+	if existing != nil {
+		if existing.SourceVolumeID != volumeID {
+			return nil, ErrIdempotentParameterMismatch
+		}
+		return existing, nil
+	}
+
+	resp, err := c.snapshotClient.Create(volumeID, &models.SnapshotCreate{Name: name})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create snapshot %q of volume %q: %v", name, volumeID, err)
+	}
+
+	return c.WaitForSnapshotState(ctx, resp.SnapshotID, SnapshotStatusAvailable)
+}
+
+// DeleteSnapshot deletes the snapshot identified by snapshotID. It is a no-op if the
+// snapshot has already been removed.
+func (c *cloud) DeleteSnapshot(ctx context.Context, snapshotID string) error {
+	if err := c.snapshotClient.Delete(snapshotID); err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			return nil
+		}
+		return fmt.Errorf("failed to delete snapshot %q: %v", snapshotID, err)
+	}
+	return nil
+}
+
+// ListSnapshots lists the snapshots for volumeID, or every snapshot owned by the service
+// instance when volumeID is empty. startingToken/the returned nextToken are opaque,
+// base64-encoded offsets into the result set.
+func (c *cloud) ListSnapshots(ctx context.Context, volumeID string, maxEntries int32, startingToken string) ([]*Snapshot, string, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, "", err
+	}
+
+	offset, err := decodePageToken(startingToken)
+	if err != nil {
+		return nil, "", err
+	}
+
+	resp, err := c.snapshotClient.GetAll()
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to list snapshots: %v", err)
+	}
+
+	var snapshots []*Snapshot
+	for _, s := range resp.Snapshots {
+		if volumeID != "" && !containsVolume(s.VolumeSnapshots, volumeID) {
+			continue
+		}
+		snapshots = append(snapshots, snapshotFromPower(s))
+	}
+
+	if offset >= len(snapshots) {
+		return nil, "", nil
+	}
+
+	end := len(snapshots)
+	nextToken := ""
+	if maxEntries > 0 && offset+int(maxEntries) < end {
+		end = offset + int(maxEntries)
+		nextToken = encodePageToken(end)
+	}
+
+	return snapshots[offset:end], nextToken, nil
+}
+
+// GetSnapshotByName returns the snapshot with the given name, or ErrNotFound if none exists.
+func (c *cloud) GetSnapshotByName(ctx context.Context, name string) (*Snapshot, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	resp, err := c.snapshotClient.GetAll()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list snapshots: %v", err)
+	}
+
+	for _, s := range resp.Snapshots {
+		if s.Name == name {
+			return snapshotFromPower(s), nil
+		}
+	}
+	return nil, ErrNotFound
+}
+
+// WaitForSnapshotState polls until the snapshot reaches the requested state, snapshotPollTimeout
+// elapses, or ctx is done - whichever comes first - so a cancelled or expired gRPC deadline
+// from the caller aborts the wait instead of blocking it.
+func (c *cloud) WaitForSnapshotState(ctx context.Context, snapshotID, state string) (*Snapshot, error) {
+	var snap *Snapshot
+	err := waitUntil(ctx, snapshotPollInterval, snapshotPollTimeout, func() (bool, error) {
+		resp, err := c.snapshotClient.Get(snapshotID)
+		if err != nil {
+			return false, err
+		}
+		snap = snapshotFromPower(resp)
+		klog.V(5).Infof("WaitForSnapshotState: snapshot %s is in state %q, waiting for %q", snapshotID, resp.Status, state)
+		return resp.Status == state, nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("snapshot %q did not reach state %q: %w", snapshotID, state, err)
+	}
+	return snap, nil
+}
+
+func snapshotFromPower(s *models.Snapshot) *Snapshot {
+	return &Snapshot{
+		SnapshotID:     s.SnapshotID,
+		SourceVolumeID: s.VolumeSnapshots[0],
+		SizeGiB:        int64(s.Size),
+		CreationTime:   time.Time(s.CreationDate),
+		ReadyToUse:     s.Status == SnapshotStatusAvailable,
+	}
+}
+
+func containsVolume(volumeIDs []string, volumeID string) bool {
+	for _, v := range volumeIDs {
+		if v == volumeID {
+			return true
+		}
+	}
+	return false
+}
+
+func encodePageToken(offset int) string {
+	return base64.StdEncoding.EncodeToString([]byte(strconv.Itoa(offset)))
+}
+
+func decodePageToken(token string) (int, error) {
+	if token == "" {
+		return 0, nil
+	}
+	raw, err := base64.StdEncoding.DecodeString(token)
+	if err != nil {
+		return 0, fmt.Errorf("invalid starting token %q: %v", token, err)
+	}
+	offset, err := strconv.Atoi(string(raw))
+	if err != nil || offset < 0 {
+		return 0, fmt.Errorf("invalid starting token %q", token)
+	}
+	return offset, nil
+}
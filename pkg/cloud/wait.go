@@ -0,0 +1,55 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cloud
+
+import (
+	"context"
+	"time"
+
+	"k8s.io/apimachinery/pkg/util/wait"
+)
+
+// timeoutCh returns a channel that closes when ctx is done or after timeout, whichever
+// happens first, for use as the stop channel of a wait.PollImmediateUntil poll loop.
+func timeoutCh(ctx context.Context, timeout time.Duration) <-chan struct{} {
+	stopCh := make(chan struct{})
+	timer := time.NewTimer(timeout)
+	go func() {
+		defer timer.Stop()
+		select {
+		case <-ctx.Done():
+		case <-timer.C:
+		}
+		close(stopCh)
+	}()
+	return stopCh
+}
+
+// waitUntil polls cond every interval until it returns true, ctx is done, or timeout
+// elapses, whichever comes first. wait.PollImmediateUntil's stop channel (timeoutCh) can
+// close well before cond's next tick, so it usually returns the generic wait.ErrWaitTimeout
+// on ctx cancellation rather than letting cond observe ctx.Err() itself - check ctx here,
+// after the poll returns, so callers reliably get the ctx error back instead.
+func waitUntil(ctx context.Context, interval, timeout time.Duration, cond wait.ConditionFunc) error {
+	if err := wait.PollImmediateUntil(interval, cond, timeoutCh(ctx, timeout)); err != nil {
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return ctxErr
+		}
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,30 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package driver
+
+const (
+	// ShareableKey is the StorageClass parameter requesting a PowerVS shareable (multi-attach) volume
+	ShareableKey = "shareable"
+
+	// VolumeModeKey is the PublishContext key carrying the requested volume mode, next to WWNKey
+	VolumeModeKey = "volumeMode"
+
+	// VolumeModeBlock is the VolumeModeKey value for raw block volumes
+	VolumeModeBlock = "block"
+	// VolumeModeFilesystem is the VolumeModeKey value for mounted filesystem volumes
+	VolumeModeFilesystem = "filesystem"
+)
@@ -18,6 +18,8 @@ package driver
 
 import (
 	"context"
+	"errors"
+	"strconv"
 	"strings"
 
 	csi "github.com/container-storage-interface/spec/lib/go/csi"
@@ -25,22 +27,39 @@ import (
 	"github.com/ppc64le-cloud/powervs-csi-driver/pkg/util"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
 	"k8s.io/klog/v2"
 )
 
 var (
-	// TODO: explore multi-node attach
+	// volumeCaps are the access modes supported by a non-shareable volume
 	volumeCaps = []csi.VolumeCapability_AccessMode{
 		{
 			Mode: csi.VolumeCapability_AccessMode_SINGLE_NODE_WRITER,
 		},
 	}
 
+	// shareableVolumeCaps are the additional access modes supported by a PowerVS shareable
+	// volume, on top of volumeCaps.
+	shareableVolumeCaps = []csi.VolumeCapability_AccessMode{
+		{
+			Mode: csi.VolumeCapability_AccessMode_MULTI_NODE_MULTI_WRITER,
+		},
+		{
+			Mode: csi.VolumeCapability_AccessMode_MULTI_NODE_READER_ONLY,
+		},
+	}
+
 	// controllerCaps represents the capability of controller service
 	controllerCaps = []csi.ControllerServiceCapability_RPC_Type{
 		csi.ControllerServiceCapability_RPC_CREATE_DELETE_VOLUME,
 		csi.ControllerServiceCapability_RPC_PUBLISH_UNPUBLISH_VOLUME,
 		csi.ControllerServiceCapability_RPC_EXPAND_VOLUME,
+		csi.ControllerServiceCapability_RPC_CREATE_DELETE_SNAPSHOT,
+		csi.ControllerServiceCapability_RPC_LIST_SNAPSHOTS,
+		csi.ControllerServiceCapability_RPC_LIST_VOLUMES,
+		csi.ControllerServiceCapability_RPC_LIST_VOLUMES_PUBLISHED_NODES,
+		csi.ControllerServiceCapability_RPC_GET_VOLUME,
 	}
 )
 
@@ -48,6 +67,7 @@ var (
 type controllerService struct {
 	cloud         cloud.Cloud
 	driverOptions *Options
+	volumeLocks   *util.VolumeLocks
 }
 
 var (
@@ -58,7 +78,7 @@ var (
 // it panics if failed to create the service
 func newControllerService(driverOptions *Options) controllerService {
 	klog.V(4).Infof("retrieving node info from metadata service")
-	metadata, err := cloud.NewMetadataService(cloud.DefaultKubernetesAPIClient)
+	metadata, err := cloud.NewMetadataService(cloud.DefaultKubernetesAPIClient, driverOptions.metadataSource)
 	if err != nil {
 		panic(err)
 	}
@@ -71,6 +91,7 @@ func newControllerService(driverOptions *Options) controllerService {
 	return controllerService{
 		cloud:         c,
 		driverOptions: driverOptions,
+		volumeLocks:   util.NewVolumeLocks(),
 	}
 }
 
@@ -81,6 +102,11 @@ func (d *controllerService) CreateVolume(ctx context.Context, req *csi.CreateVol
 		return nil, status.Error(codes.InvalidArgument, "Volume name not provided")
 	}
 
+	if !d.volumeLocks.TryAcquire(volName) {
+		return nil, status.Errorf(codes.Aborted, "operation already exists for volume %s", volName)
+	}
+	defer d.volumeLocks.Release(volName)
+
 	volSizeBytes, err := getVolSizeBytes(req)
 	if err != nil {
 		return nil, err
@@ -91,31 +117,54 @@ func (d *controllerService) CreateVolume(ctx context.Context, req *csi.CreateVol
 		return nil, status.Error(codes.InvalidArgument, "Volume capabilities not provided")
 	}
 
-	if !isValidVolumeCapabilities(volCaps) {
-		modes := util.GetAccessModes(volCaps)
-		stringModes := strings.Join(*modes, ", ")
-		errString := "Volume capabilities " + stringModes + " not supported. Only AccessModes[ReadWriteOnce] supported."
-		return nil, status.Error(codes.InvalidArgument, errString)
-	}
-
 	var volumeType string
+	var shareable bool
 
 	for key, value := range req.GetParameters() {
 		switch strings.ToLower(key) {
 		case VolumeTypeKey:
 			volumeType = value
+		case ShareableKey:
+			shareable, err = strconv.ParseBool(value)
+			if err != nil {
+				return nil, status.Errorf(codes.InvalidArgument, "Invalid value %q for parameter %s", value, key)
+			}
 		default:
 			return nil, status.Errorf(codes.InvalidArgument, "Invalid parameter key %s for CreateVolume", key)
 		}
 	}
+	shareable = shareable || util.HasMultiNodeAccessMode(volCaps)
+
+	if !isValidVolumeCapabilities(volCaps, shareable) {
+		modes := util.GetAccessModes(volCaps)
+		stringModes := strings.Join(*modes, ", ")
+		errString := "Volume capabilities " + stringModes + " not supported."
+		return nil, status.Error(codes.InvalidArgument, errString)
+	}
+
+	var snapshotID string
+	if volumeSource := req.GetVolumeContentSource(); volumeSource != nil {
+		if _, ok := volumeSource.GetType().(*csi.VolumeContentSource_Snapshot); !ok {
+			return nil, status.Error(codes.InvalidArgument, "Unsupported volumeContentSource type")
+		}
+		sourceSnapshot := volumeSource.GetSnapshot()
+		if sourceSnapshot == nil {
+			return nil, status.Error(codes.InvalidArgument, "Error retrieving snapshot from the volumeContentSource")
+		}
+		snapshotID = sourceSnapshot.GetSnapshotId()
+		if len(snapshotID) == 0 {
+			return nil, status.Error(codes.InvalidArgument, "Snapshot ID not provided")
+		}
+	}
 
 	opts := &cloud.DiskOptions{
-		Shareable:     false,
+		Shareable:     shareable,
 		CapacityBytes: volSizeBytes,
 		VolumeType:    volumeType,
+		SnapshotID:    snapshotID,
 	}
 
-	disk, err := d.cloud.CreateDisk(volName, opts)
+	disk, err := d.cloud.CreateDisk(ctx, volName, opts)
 	if err != nil {
 		errCode := codes.Internal
 		if err == cloud.ErrNotFound {
@@ -124,9 +173,10 @@ func (d *controllerService) CreateVolume(ctx context.Context, req *csi.CreateVol
 		if err == cloud.ErrIdempotentParameterMismatch {
 			errCode = codes.AlreadyExists
 		}
+		errCode = errToCode(err, errCode)
 		return nil, status.Errorf(errCode, "Could not create volume %q: %v", volName, err)
 	}
-	return newCreateVolumeResponse(disk), nil
+	return newCreateVolumeResponse(disk, req.GetVolumeContentSource()), nil
 }
 
 func (d *controllerService) DeleteVolume(ctx context.Context, req *csi.DeleteVolumeRequest) (*csi.DeleteVolumeResponse, error) {
@@ -136,15 +186,20 @@ func (d *controllerService) DeleteVolume(ctx context.Context, req *csi.DeleteVol
 		return nil, status.Error(codes.InvalidArgument, "Volume ID not provided")
 	}
 
-	if _, err := d.cloud.GetDiskByID(volumeID); err != nil {
+	if !d.volumeLocks.TryAcquire(volumeID) {
+		return nil, status.Errorf(codes.Aborted, "operation already exists for volume %s", volumeID)
+	}
+	defer d.volumeLocks.Release(volumeID)
+
+	if _, err := d.cloud.GetDiskByID(ctx, volumeID); err != nil {
 		if err == cloud.ErrNotFound {
 			klog.V(4).Info("DeleteVolume: volume not found, returning with success")
 			return &csi.DeleteVolumeResponse{}, nil
 		}
 	}
 
-	if _, err := d.cloud.DeleteDisk(volumeID); err != nil {
-		return nil, status.Errorf(codes.Internal, "Could not delete volume ID %q: %v", volumeID, err)
+	if _, err := d.cloud.DeleteDisk(ctx, volumeID); err != nil {
+		return nil, status.Errorf(errToCode(err, codes.Internal), "Could not delete volume ID %q: %v", volumeID, err)
 	}
 
 	return &csi.DeleteVolumeResponse{}, nil
@@ -162,46 +217,63 @@ func (d *controllerService) ControllerPublishVolume(ctx context.Context, req *cs
 		return nil, status.Error(codes.InvalidArgument, "Node ID not provided")
 	}
 
+	if !d.volumeLocks.TryAcquire(volumeID) {
+		return nil, status.Errorf(codes.Aborted, "operation already exists for volume %s", volumeID)
+	}
+	defer d.volumeLocks.Release(volumeID)
+
+	if !d.volumeLocks.TryAcquire(nodeID) {
+		return nil, status.Errorf(codes.Aborted, "operation already exists for node %s", nodeID)
+	}
+	defer d.volumeLocks.Release(nodeID)
+
 	volCap := req.GetVolumeCapability()
 	if volCap == nil {
 		return nil, status.Error(codes.InvalidArgument, "Volume capability not provided")
 	}
 
-	caps := []*csi.VolumeCapability{volCap}
-	if !isValidVolumeCapabilities(caps) {
-		modes := util.GetAccessModes(caps)
-		stringModes := strings.Join(*modes, ", ")
-		errString := "Volume capabilities " + stringModes + " not supported. Only AccessModes[ReadWriteOnce] supported."
-		return nil, status.Error(codes.InvalidArgument, errString)
-	}
-
-	if _, err := d.cloud.GetPVMInstanceByID(nodeID); err != nil {
+	if _, err := d.cloud.GetPVMInstanceByID(ctx, nodeID); err != nil {
 		return nil, status.Errorf(codes.NotFound, "Instance %q not found, err: %v", nodeID, err)
 	}
 
-	disk, err := d.cloud.GetDiskByID(volumeID)
+	disk, err := d.cloud.GetDiskByID(ctx, volumeID)
 
 	if err != nil {
 		if err == cloud.ErrNotFound {
 			return nil, status.Error(codes.NotFound, "Volume not found")
 		}
-		return nil, status.Errorf(codes.Internal, "Could not get volume with ID %q: %v", volumeID, err)
+		return nil, status.Errorf(errToCode(err, codes.Internal), "Could not get volume with ID %q: %v", volumeID, err)
 	}
 
-	pvInfo := map[string]string{WWNKey: disk.WWN}
+	caps := []*csi.VolumeCapability{volCap}
+	if !isValidVolumeCapabilities(caps, disk.Shareable) {
+		modes := util.GetAccessModes(caps)
+		stringModes := strings.Join(*modes, ", ")
+		errString := "Volume capabilities " + stringModes + " not supported."
+		return nil, status.Error(codes.InvalidArgument, errString)
+	}
+
+	volumeMode := VolumeModeFilesystem
+	if volCap.GetBlock() != nil {
+		volumeMode = VolumeModeBlock
+	}
+	pvInfo := map[string]string{
+		WWNKey:        disk.WWN,
+		VolumeModeKey: volumeMode,
+	}
 
-	attached, err := d.cloud.IsAttached(volumeID, nodeID)
+	attached, err := d.cloud.IsAttached(ctx, volumeID, nodeID)
 	if attached {
 		klog.V(5).Infof("ControllerPublishVolume: volume %s already attached to node %s, returning success", volumeID, nodeID)
 		return &csi.ControllerPublishVolumeResponse{PublishContext: pvInfo}, nil
 	}
 
-	err = d.cloud.AttachDisk(volumeID, nodeID)
+	err = d.cloud.AttachDisk(ctx, volumeID, nodeID)
 	if err != nil {
 		if err == cloud.ErrAlreadyExists {
 			return nil, status.Error(codes.AlreadyExists, err.Error())
 		}
-		return nil, status.Errorf(codes.Internal, "Could not attach volume %q to node %q: %v", volumeID, nodeID, err)
+		return nil, status.Errorf(errToCode(err, codes.Internal), "Could not attach volume %q to node %q: %v", volumeID, nodeID, err)
 	}
 	klog.V(5).Infof("ControllerPublishVolume: volume %s attached to node %s", volumeID, nodeID)
 
@@ -220,20 +292,30 @@ func (d *controllerService) ControllerUnpublishVolume(ctx context.Context, req *
 		return nil, status.Error(codes.InvalidArgument, "Node ID not provided")
 	}
 
-	if _, err := d.cloud.GetDiskByID(volumeID); err != nil {
+	if !d.volumeLocks.TryAcquire(volumeID) {
+		return nil, status.Errorf(codes.Aborted, "operation already exists for volume %s", volumeID)
+	}
+	defer d.volumeLocks.Release(volumeID)
+
+	if !d.volumeLocks.TryAcquire(nodeID) {
+		return nil, status.Errorf(codes.Aborted, "operation already exists for node %s", nodeID)
+	}
+	defer d.volumeLocks.Release(nodeID)
+
+	if _, err := d.cloud.GetDiskByID(ctx, volumeID); err != nil {
 		if err == cloud.ErrNotFound {
 			klog.V(4).Info("ControllerUnpublishVolume: volume not found, returning with success")
 			return &csi.ControllerUnpublishVolumeResponse{}, nil
 		}
 	}
 
-	if attached, err := d.cloud.IsAttached(volumeID, nodeID); !attached {
+	if attached, err := d.cloud.IsAttached(ctx, volumeID, nodeID); !attached {
 		klog.V(4).Infof("ControllerUnpublishVolume: volume %s is not attached to %s, err: %v, returning with success", volumeID, nodeID, err)
 		return &csi.ControllerUnpublishVolumeResponse{}, nil
 	}
 
-	if err := d.cloud.DetachDisk(volumeID, nodeID); err != nil {
-		return nil, status.Errorf(codes.Internal, "Could not detach volume %q from node %q: %v", volumeID, nodeID, err)
+	if err := d.cloud.DetachDisk(ctx, volumeID, nodeID); err != nil {
+		return nil, status.Errorf(errToCode(err, codes.Internal), "Could not detach volume %q from node %q: %v", volumeID, nodeID, err)
 	}
 	klog.V(5).Infof("ControllerUnpublishVolume: volume %s detached from node %s", volumeID, nodeID)
 
@@ -263,7 +345,28 @@ func (d *controllerService) GetCapacity(ctx context.Context, req *csi.GetCapacit
 
 func (d *controllerService) ListVolumes(ctx context.Context, req *csi.ListVolumesRequest) (*csi.ListVolumesResponse, error) {
 	klog.V(4).Infof("ListVolumes: called with args %+v", *req)
-	return nil, status.Error(codes.Unimplemented, "")
+	disks, nextToken, err := d.cloud.ListDisks(ctx, req.GetMaxEntries(), req.GetStartingToken())
+	if err != nil {
+		return nil, status.Errorf(errToCode(err, codes.Internal), "Could not list volumes: %v", err)
+	}
+
+	entries := make([]*csi.ListVolumesResponse_Entry, 0, len(disks))
+	for _, disk := range disks {
+		entries = append(entries, &csi.ListVolumesResponse_Entry{
+			Volume: &csi.Volume{
+				VolumeId:      disk.VolumeID,
+				CapacityBytes: util.GiBToBytes(disk.CapacityGiB),
+			},
+			Status: &csi.ListVolumesResponse_VolumeStatus{
+				PublishedNodeIds: disk.AttachedNodes,
+			},
+		})
+	}
+
+	return &csi.ListVolumesResponse{
+		Entries:   entries,
+		NextToken: nextToken,
+	}, nil
 }
 
 func (d *controllerService) ValidateVolumeCapabilities(ctx context.Context, req *csi.ValidateVolumeCapabilitiesRequest) (*csi.ValidateVolumeCapabilitiesResponse, error) {
@@ -278,15 +381,16 @@ func (d *controllerService) ValidateVolumeCapabilities(ctx context.Context, req
 		return nil, status.Error(codes.InvalidArgument, "Volume capabilities not provided")
 	}
 
-	if _, err := d.cloud.GetDiskByID(volumeID); err != nil {
+	disk, err := d.cloud.GetDiskByID(ctx, volumeID)
+	if err != nil {
 		if err == cloud.ErrNotFound {
 			return nil, status.Error(codes.NotFound, "Volume not found")
 		}
-		return nil, status.Errorf(codes.Internal, "Could not get volume with ID %q: %v", volumeID, err)
+		return nil, status.Errorf(errToCode(err, codes.Internal), "Could not get volume with ID %q: %v", volumeID, err)
 	}
 
 	var confirmed *csi.ValidateVolumeCapabilitiesResponse_Confirmed
-	if isValidVolumeCapabilities(volCaps) {
+	if isValidVolumeCapabilities(volCaps, disk.Shareable) {
 		confirmed = &csi.ValidateVolumeCapabilitiesResponse_Confirmed{VolumeCapabilities: volCaps}
 	}
 	return &csi.ValidateVolumeCapabilitiesResponse{
@@ -301,6 +405,11 @@ func (d *controllerService) ControllerExpandVolume(ctx context.Context, req *csi
 		return nil, status.Error(codes.InvalidArgument, "Volume ID not provided")
 	}
 
+	if !d.volumeLocks.TryAcquire(volumeID) {
+		return nil, status.Errorf(codes.Aborted, "operation already exists for volume %s", volumeID)
+	}
+	defer d.volumeLocks.Release(volumeID)
+
 	capRange := req.GetCapacityRange()
 	if capRange == nil {
 		return nil, status.Error(codes.InvalidArgument, "Capacity range not provided")
@@ -312,9 +421,9 @@ func (d *controllerService) ControllerExpandVolume(ctx context.Context, req *csi
 		return nil, status.Error(codes.InvalidArgument, "After round-up, volume size exceeds the limit specified")
 	}
 
-	actualSizeGiB, err := d.cloud.ResizeDisk(volumeID, newSize)
+	actualSizeGiB, err := d.cloud.ResizeDisk(ctx, volumeID, newSize)
 	if err != nil {
-		return nil, status.Errorf(codes.Internal, "Could not resize volume %q: %v", volumeID, err)
+		return nil, status.Errorf(errToCode(err, codes.Internal), "Could not resize volume %q: %v", volumeID, err)
 	}
 
 	return &csi.ControllerExpandVolumeResponse{
@@ -325,12 +434,55 @@ func (d *controllerService) ControllerExpandVolume(ctx context.Context, req *csi
 
 func (d *controllerService) ControllerGetVolume(ctx context.Context, req *csi.ControllerGetVolumeRequest) (*csi.ControllerGetVolumeResponse, error) {
 	klog.V(4).Infof("ControllerGetVolume: called with args %+v", *req)
-	return nil, status.Error(codes.Unimplemented, "")
+	volumeID := req.GetVolumeId()
+	if len(volumeID) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "Volume ID not provided")
+	}
+
+	disk, err := d.cloud.GetDiskByID(ctx, volumeID)
+	if err != nil {
+		if err == cloud.ErrNotFound {
+			return nil, status.Error(codes.NotFound, "Volume not found")
+		}
+		return nil, status.Errorf(errToCode(err, codes.Internal), "Could not get volume with ID %q: %v", volumeID, err)
+	}
+
+	return &csi.ControllerGetVolumeResponse{
+		Volume: &csi.Volume{
+			VolumeId:      disk.VolumeID,
+			CapacityBytes: util.GiBToBytes(disk.CapacityGiB),
+		},
+		Status: &csi.ControllerGetVolumeResponse_VolumeStatus{
+			PublishedNodeIds: disk.AttachedNodes,
+		},
+	}, nil
 }
 
-func isValidVolumeCapabilities(volCaps []*csi.VolumeCapability) bool {
+// errToCode maps ctx cancellation/deadline errors bubbled up from a cloud wait loop onto
+// the gRPC codes external-attacher/external-provisioner know to retry on, falling back to
+// fallback for anything else.
+func errToCode(err error, fallback codes.Code) codes.Code {
+	switch {
+	case errors.Is(err, context.DeadlineExceeded):
+		return codes.DeadlineExceeded
+	case errors.Is(err, context.Canceled):
+		return codes.Canceled
+	default:
+		return fallback
+	}
+}
+
+func isValidVolumeCapabilities(volCaps []*csi.VolumeCapability, shareable bool) bool {
+	supportedModes := volumeCaps
+	if shareable {
+		supportedModes = append(append([]csi.VolumeCapability_AccessMode{}, volumeCaps...), shareableVolumeCaps...)
+	}
+
 	hasSupport := func(cap *csi.VolumeCapability) bool {
-		for _, c := range volumeCaps {
+		if cap.GetBlock() == nil && cap.GetMount() == nil {
+			return false
+		}
+		for _, c := range supportedModes {
 			if c.GetMode() == cap.AccessMode.GetMode() {
 				return true
 			}
@@ -349,22 +501,60 @@ func isValidVolumeCapabilities(volCaps []*csi.VolumeCapability) bool {
 
 func (d *controllerService) CreateSnapshot(ctx context.Context, req *csi.CreateSnapshotRequest) (*csi.CreateSnapshotResponse, error) {
 	klog.V(4).Infof("CreateSnapshot: called with args %+v", req)
-	return nil, status.Error(codes.Unimplemented, "")
+	volumeID := req.GetSourceVolumeId()
+	if len(volumeID) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "Source volume ID not provided")
+	}
+
+	name := req.GetName()
+	if len(name) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "Snapshot name not provided")
+	}
+
+	snapshot, err := d.cloud.CreateSnapshot(ctx, volumeID, name)
+	if err != nil {
+		if err == cloud.ErrIdempotentParameterMismatch {
+			return nil, status.Errorf(codes.AlreadyExists, "Snapshot %q already exists for a different volume", name)
+		}
+		return nil, status.Errorf(errToCode(err, codes.Internal), "Could not create snapshot %q: %v", name, err)
+	}
+
+	return newCreateSnapshotResponse(snapshot), nil
 }
 
 func (d *controllerService) DeleteSnapshot(ctx context.Context, req *csi.DeleteSnapshotRequest) (*csi.DeleteSnapshotResponse, error) {
 	klog.V(4).Infof("DeleteSnapshot: called with args %+v", req)
-	return nil, status.Error(codes.Unimplemented, "")
+	snapshotID := req.GetSnapshotId()
+	if len(snapshotID) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "Snapshot ID not provided")
+	}
+
+	if err := d.cloud.DeleteSnapshot(ctx, snapshotID); err != nil {
+		return nil, status.Errorf(errToCode(err, codes.Internal), "Could not delete snapshot %q: %v", snapshotID, err)
+	}
+
+	return &csi.DeleteSnapshotResponse{}, nil
 }
 
 func (d *controllerService) ListSnapshots(ctx context.Context, req *csi.ListSnapshotsRequest) (*csi.ListSnapshotsResponse, error) {
 	klog.V(4).Infof("ListSnapshots: called with args %+v", req)
-	return nil, status.Error(codes.Unimplemented, "")
-}
+	snapshots, nextToken, err := d.cloud.ListSnapshots(ctx, req.GetSourceVolumeId(), req.GetMaxEntries(), req.GetStartingToken())
+	if err != nil {
+		return nil, status.Errorf(errToCode(err, codes.Internal), "Could not list snapshots: %v", err)
+	}
+
+	entries := make([]*csi.ListSnapshotsResponse_Entry, 0, len(snapshots))
+	for _, s := range snapshots {
+		entries = append(entries, &csi.ListSnapshotsResponse_Entry{Snapshot: newSnapshot(s)})
+	}
 
-func newCreateVolumeResponse(disk *cloud.Disk) *csi.CreateVolumeResponse {
-	var src *csi.VolumeContentSource
+	return &csi.ListSnapshotsResponse{
+		Entries:   entries,
+		NextToken: nextToken,
+	}, nil
+}
 
+func newCreateVolumeResponse(disk *cloud.Disk, src *csi.VolumeContentSource) *csi.CreateVolumeResponse {
 	return &csi.CreateVolumeResponse{
 		Volume: &csi.Volume{
 			VolumeId:      disk.VolumeID,
@@ -375,6 +565,20 @@ func newCreateVolumeResponse(disk *cloud.Disk) *csi.CreateVolumeResponse {
 	}
 }
 
+func newCreateSnapshotResponse(snapshot *cloud.Snapshot) *csi.CreateSnapshotResponse {
+	return &csi.CreateSnapshotResponse{Snapshot: newSnapshot(snapshot)}
+}
+
+func newSnapshot(snapshot *cloud.Snapshot) *csi.Snapshot {
+	return &csi.Snapshot{
+		SnapshotId:     snapshot.SnapshotID,
+		SourceVolumeId: snapshot.SourceVolumeID,
+		SizeBytes:      util.GiBToBytes(snapshot.SizeGiB),
+		CreationTime:   timestamppb.New(snapshot.CreationTime),
+		ReadyToUse:     snapshot.ReadyToUse,
+	}
+}
+
 func getVolSizeBytes(req *csi.CreateVolumeRequest) (int64, error) {
 	var volSizeBytes int64
 	capRange := req.GetCapacityRange()
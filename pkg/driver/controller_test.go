@@ -0,0 +1,516 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package driver
+
+import (
+	"context"
+	"encoding/base64"
+	"strconv"
+	"sync"
+	"testing"
+
+	csi "github.com/container-storage-interface/spec/lib/go/csi"
+	"github.com/ppc64le-cloud/powervs-csi-driver/pkg/cloud"
+	"github.com/ppc64le-cloud/powervs-csi-driver/pkg/util"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// fakeCloud is a minimal in-memory stand-in for cloud.Cloud, keyed the same way the real
+// PowerVS-backed implementation is, so controllerService tests don't need a live service
+// instance.
+type fakeCloud struct {
+	mu        sync.Mutex
+	nextID    int
+	disks     map[string]*cloud.Disk
+	diskOrder []string
+	snapshots map[string]*cloud.Snapshot
+	attached  map[string]map[string]bool
+	instances map[string]bool
+
+	// attachHook, when set, runs synchronously at the start of AttachDisk, letting tests
+	// hold an attach "in flight" to exercise locking around concurrent callers.
+	attachHook func()
+}
+
+func newFakeCloud() *fakeCloud {
+	return &fakeCloud{
+		disks:     make(map[string]*cloud.Disk),
+		snapshots: make(map[string]*cloud.Snapshot),
+		attached:  make(map[string]map[string]bool),
+		instances: make(map[string]bool),
+	}
+}
+
+func (f *fakeCloud) newID(prefix string) string {
+	f.nextID++
+	return prefix + "-" + string(rune('a'+f.nextID))
+}
+
+func (f *fakeCloud) CreateDisk(ctx context.Context, volumeName string, diskOptions *cloud.DiskOptions) (*cloud.Disk, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if diskOptions.SnapshotID != "" {
+		if _, ok := f.snapshots[diskOptions.SnapshotID]; !ok {
+			return nil, cloud.ErrNotFound
+		}
+	}
+
+	disk := &cloud.Disk{
+		VolumeID:    f.newID("vol"),
+		WWN:         f.newID("wwn"),
+		CapacityGiB: diskOptions.CapacityBytes / (1024 * 1024 * 1024),
+		Shareable:   diskOptions.Shareable,
+	}
+	f.disks[disk.VolumeID] = disk
+	f.diskOrder = append(f.diskOrder, disk.VolumeID)
+	return disk, nil
+}
+
+func (f *fakeCloud) DeleteDisk(ctx context.Context, volumeID string) (bool, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	delete(f.disks, volumeID)
+	for i, id := range f.diskOrder {
+		if id == volumeID {
+			f.diskOrder = append(f.diskOrder[:i], f.diskOrder[i+1:]...)
+			break
+		}
+	}
+	return true, nil
+}
+
+func (f *fakeCloud) AttachDisk(ctx context.Context, volumeID, nodeID string) error {
+	if f.attachHook != nil {
+		f.attachHook()
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.attached[volumeID] == nil {
+		f.attached[volumeID] = make(map[string]bool)
+	}
+	f.attached[volumeID][nodeID] = true
+	if disk, ok := f.disks[volumeID]; ok {
+		disk.AttachedNodes = append(disk.AttachedNodes, nodeID)
+	}
+	return nil
+}
+
+func (f *fakeCloud) DetachDisk(ctx context.Context, volumeID, nodeID string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	delete(f.attached[volumeID], nodeID)
+	return nil
+}
+
+func (f *fakeCloud) ResizeDisk(ctx context.Context, volumeID string, newSizeBytes int64) (int32, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	disk, ok := f.disks[volumeID]
+	if !ok {
+		return 0, cloud.ErrNotFound
+	}
+	disk.CapacityGiB = newSizeBytes / (1024 * 1024 * 1024)
+	return int32(disk.CapacityGiB), nil
+}
+
+func (f *fakeCloud) GetDiskByID(ctx context.Context, volumeID string) (*cloud.Disk, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	disk, ok := f.disks[volumeID]
+	if !ok {
+		return nil, cloud.ErrNotFound
+	}
+	return disk, nil
+}
+
+// ListDisks mirrors the opaque, base64-encoded-offset pagination scheme used by the real
+// cloud.ListDisks/ListSnapshots implementations, ordered by creation.
+func (f *fakeCloud) ListDisks(ctx context.Context, maxEntries int32, startingToken string) ([]*cloud.Disk, string, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	offset := 0
+	if startingToken != "" {
+		raw, err := base64.StdEncoding.DecodeString(startingToken)
+		if err != nil {
+			return nil, "", err
+		}
+		offset, err = strconv.Atoi(string(raw))
+		if err != nil {
+			return nil, "", err
+		}
+	}
+
+	if offset >= len(f.diskOrder) {
+		return nil, "", nil
+	}
+
+	end := len(f.diskOrder)
+	nextToken := ""
+	if maxEntries > 0 && offset+int(maxEntries) < end {
+		end = offset + int(maxEntries)
+		nextToken = base64.StdEncoding.EncodeToString([]byte(strconv.Itoa(end)))
+	}
+
+	disks := make([]*cloud.Disk, 0, end-offset)
+	for _, id := range f.diskOrder[offset:end] {
+		disks = append(disks, f.disks[id])
+	}
+	return disks, nextToken, nil
+}
+
+func (f *fakeCloud) IsAttached(ctx context.Context, volumeID, nodeID string) (bool, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.attached[volumeID][nodeID], nil
+}
+
+func (f *fakeCloud) GetPVMInstanceByID(ctx context.Context, instanceID string) (*cloud.PVMInstance, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return &cloud.PVMInstance{ID: instanceID}, nil
+}
+
+func (f *fakeCloud) CreateSnapshot(ctx context.Context, volumeID, name string) (*cloud.Snapshot, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	for _, s := range f.snapshots {
+		if s.SourceVolumeID == volumeID && s.SnapshotID == name {
+			return s, nil
+		}
+	}
+
+	snap := &cloud.Snapshot{
+		SnapshotID:     name,
+		SourceVolumeID: volumeID,
+		SizeGiB:        1,
+		ReadyToUse:     true,
+	}
+	f.snapshots[snap.SnapshotID] = snap
+	return snap, nil
+}
+
+func (f *fakeCloud) DeleteSnapshot(ctx context.Context, snapshotID string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	delete(f.snapshots, snapshotID)
+	return nil
+}
+
+func (f *fakeCloud) ListSnapshots(ctx context.Context, volumeID string, maxEntries int32, startingToken string) ([]*cloud.Snapshot, string, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	var out []*cloud.Snapshot
+	for _, s := range f.snapshots {
+		if volumeID == "" || s.SourceVolumeID == volumeID {
+			out = append(out, s)
+		}
+	}
+	return out, "", nil
+}
+
+func (f *fakeCloud) GetSnapshotByName(ctx context.Context, name string) (*cloud.Snapshot, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if s, ok := f.snapshots[name]; ok {
+		return s, nil
+	}
+	return nil, cloud.ErrNotFound
+}
+
+func newTestControllerService(c cloud.Cloud) *controllerService {
+	return &controllerService{
+		cloud:         c,
+		driverOptions: &Options{},
+		volumeLocks:   util.NewVolumeLocks(),
+	}
+}
+
+func TestCreateSnapshot(t *testing.T) {
+	d := newTestControllerService(newFakeCloud())
+	req := &csi.CreateSnapshotRequest{
+		SourceVolumeId: "vol-1",
+		Name:           "snap-1",
+	}
+
+	resp, err := d.CreateSnapshot(context.Background(), req)
+	if err != nil {
+		t.Fatalf("CreateSnapshot failed: %v", err)
+	}
+	if got := resp.GetSnapshot().GetSnapshotId(); got != "snap-1" {
+		t.Errorf("got snapshot ID %q, want %q", got, "snap-1")
+	}
+	if got := resp.GetSnapshot().GetSourceVolumeId(); got != "vol-1" {
+		t.Errorf("got source volume ID %q, want %q", got, "vol-1")
+	}
+
+	// Calling CreateSnapshot again with the same name must be idempotent.
+	resp2, err := d.CreateSnapshot(context.Background(), req)
+	if err != nil {
+		t.Fatalf("second CreateSnapshot failed: %v", err)
+	}
+	if resp2.GetSnapshot().GetSnapshotId() != resp.GetSnapshot().GetSnapshotId() {
+		t.Errorf("repeated CreateSnapshot for the same name returned a different snapshot")
+	}
+}
+
+func TestDeleteSnapshot(t *testing.T) {
+	fc := newFakeCloud()
+	d := newTestControllerService(fc)
+	if _, err := fc.CreateSnapshot(context.Background(), "vol-1", "snap-1"); err != nil {
+		t.Fatalf("setup: %v", err)
+	}
+
+	_, err := d.DeleteSnapshot(context.Background(), &csi.DeleteSnapshotRequest{SnapshotId: "snap-1"})
+	if err != nil {
+		t.Fatalf("DeleteSnapshot failed: %v", err)
+	}
+	if _, err := fc.GetSnapshotByName(context.Background(), "snap-1"); err != cloud.ErrNotFound {
+		t.Errorf("expected snapshot to be gone after delete, got err=%v", err)
+	}
+}
+
+func TestCreateVolumeFromSnapshot(t *testing.T) {
+	fc := newFakeCloud()
+	d := newTestControllerService(fc)
+	if _, err := fc.CreateSnapshot(context.Background(), "vol-src", "snap-1"); err != nil {
+		t.Fatalf("setup: %v", err)
+	}
+
+	req := &csi.CreateVolumeRequest{
+		Name:               "vol-clone",
+		VolumeCapabilities: []*csi.VolumeCapability{{AccessMode: &csi.VolumeCapability_AccessMode{Mode: csi.VolumeCapability_AccessMode_SINGLE_NODE_WRITER}, AccessType: &csi.VolumeCapability_Mount{Mount: &csi.VolumeCapability_MountVolume{}}}},
+		VolumeContentSource: &csi.VolumeContentSource{
+			Type: &csi.VolumeContentSource_Snapshot{
+				Snapshot: &csi.VolumeContentSource_SnapshotSource{SnapshotId: "snap-1"},
+			},
+		},
+	}
+
+	resp, err := d.CreateVolume(context.Background(), req)
+	if err != nil {
+		t.Fatalf("CreateVolume from snapshot failed: %v", err)
+	}
+	if resp.GetVolume().GetContentSource().GetSnapshot().GetSnapshotId() != "snap-1" {
+		t.Errorf("CreateVolumeResponse did not echo back the source snapshot")
+	}
+}
+
+func TestCreateVolumeFromMissingSnapshot(t *testing.T) {
+	d := newTestControllerService(newFakeCloud())
+	req := &csi.CreateVolumeRequest{
+		Name:               "vol-clone",
+		VolumeCapabilities: []*csi.VolumeCapability{{AccessMode: &csi.VolumeCapability_AccessMode{Mode: csi.VolumeCapability_AccessMode_SINGLE_NODE_WRITER}, AccessType: &csi.VolumeCapability_Mount{Mount: &csi.VolumeCapability_MountVolume{}}}},
+		VolumeContentSource: &csi.VolumeContentSource{
+			Type: &csi.VolumeContentSource_Snapshot{
+				Snapshot: &csi.VolumeContentSource_SnapshotSource{SnapshotId: "does-not-exist"},
+			},
+		},
+	}
+
+	_, err := d.CreateVolume(context.Background(), req)
+	if status.Code(err) != codes.NotFound {
+		t.Errorf("expected codes.NotFound for a missing snapshot, got %v", err)
+	}
+}
+
+// TestConcurrentControllerPublishVolume verifies that two ControllerPublishVolume calls for
+// the same volumeID cannot run concurrently: the second call must be rejected with
+// codes.Aborted while the first is still holding the volume's lock, per util.VolumeLocks.
+func TestConcurrentControllerPublishVolume(t *testing.T) {
+	fc := newFakeCloud()
+	fc.disks["vol-1"] = &cloud.Disk{VolumeID: "vol-1", WWN: "wwn-1"}
+
+	inFlight := make(chan struct{})
+	release := make(chan struct{})
+	fc.attachHook = func() {
+		close(inFlight)
+		<-release
+	}
+	d := newTestControllerService(fc)
+
+	volCap := &csi.VolumeCapability{
+		AccessMode: &csi.VolumeCapability_AccessMode{Mode: csi.VolumeCapability_AccessMode_SINGLE_NODE_WRITER},
+		AccessType: &csi.VolumeCapability_Mount{Mount: &csi.VolumeCapability_MountVolume{}},
+	}
+	req := &csi.ControllerPublishVolumeRequest{
+		VolumeId:         "vol-1",
+		NodeId:           "node-1",
+		VolumeCapability: volCap,
+	}
+
+	firstErrCh := make(chan error, 1)
+	go func() {
+		_, err := d.ControllerPublishVolume(context.Background(), req)
+		firstErrCh <- err
+	}()
+
+	<-inFlight
+
+	_, secondErr := d.ControllerPublishVolume(context.Background(), req)
+	if status.Code(secondErr) != codes.Aborted {
+		t.Errorf("expected concurrent ControllerPublishVolume to return codes.Aborted, got %v", secondErr)
+	}
+
+	close(release)
+	if err := <-firstErrCh; err != nil {
+		t.Errorf("first ControllerPublishVolume call failed: %v", err)
+	}
+}
+
+// TestConcurrentControllerUnpublishVolume mirrors TestConcurrentControllerPublishVolume for
+// the unpublish path, which acquires the same per-volumeID/per-nodeID locks.
+func TestConcurrentControllerUnpublishVolume(t *testing.T) {
+	fc := newFakeCloud()
+	fc.disks["vol-1"] = &cloud.Disk{VolumeID: "vol-1", WWN: "wwn-1"}
+	fc.attached["vol-1"] = map[string]bool{"node-1": true}
+
+	inFlight := make(chan struct{})
+	release := make(chan struct{})
+
+	d := newTestControllerService(fc)
+	// DetachDisk has no hook of its own; acquire the volumeLocks directly to simulate an
+	// in-flight unpublish the same way the controller would hold it.
+	if !d.volumeLocks.TryAcquire("vol-1") {
+		t.Fatalf("setup: could not acquire volume lock")
+	}
+	go func() {
+		close(inFlight)
+		<-release
+		d.volumeLocks.Release("vol-1")
+	}()
+
+	<-inFlight
+	req := &csi.ControllerUnpublishVolumeRequest{VolumeId: "vol-1", NodeId: "node-1"}
+	_, err := d.ControllerUnpublishVolume(context.Background(), req)
+	if status.Code(err) != codes.Aborted {
+		t.Errorf("expected ControllerUnpublishVolume to return codes.Aborted while the volume lock is held, got %v", err)
+	}
+
+	close(release)
+}
+
+func TestIsValidVolumeCapabilitiesBlock(t *testing.T) {
+	blockCap := []*csi.VolumeCapability{{
+		AccessMode: &csi.VolumeCapability_AccessMode{Mode: csi.VolumeCapability_AccessMode_SINGLE_NODE_WRITER},
+		AccessType: &csi.VolumeCapability_Block{Block: &csi.VolumeCapability_BlockVolume{}},
+	}}
+
+	if !isValidVolumeCapabilities(blockCap, false) {
+		t.Error("expected a SINGLE_NODE_WRITER block capability to be valid on a non-shareable volume")
+	}
+
+	shareableBlockCap := []*csi.VolumeCapability{{
+		AccessMode: &csi.VolumeCapability_AccessMode{Mode: csi.VolumeCapability_AccessMode_MULTI_NODE_MULTI_WRITER},
+		AccessType: &csi.VolumeCapability_Block{Block: &csi.VolumeCapability_BlockVolume{}},
+	}}
+
+	if isValidVolumeCapabilities(shareableBlockCap, false) {
+		t.Error("expected a MULTI_NODE_MULTI_WRITER block capability to be rejected on a non-shareable volume")
+	}
+	if !isValidVolumeCapabilities(shareableBlockCap, true) {
+		t.Error("expected a MULTI_NODE_MULTI_WRITER block capability to be accepted on a shareable volume")
+	}
+}
+
+func TestListVolumesPaginationBoundaries(t *testing.T) {
+	fc := newFakeCloud()
+	d := newTestControllerService(fc)
+	for i := 0; i < 5; i++ {
+		if _, err := fc.CreateDisk(context.Background(), "vol", &cloud.DiskOptions{}); err != nil {
+			t.Fatalf("setup: %v", err)
+		}
+	}
+
+	var seen []string
+	token := ""
+	for {
+		resp, err := d.ListVolumes(context.Background(), &csi.ListVolumesRequest{MaxEntries: 2, StartingToken: token})
+		if err != nil {
+			t.Fatalf("ListVolumes failed: %v", err)
+		}
+		for _, e := range resp.GetEntries() {
+			seen = append(seen, e.GetVolume().GetVolumeId())
+		}
+		if resp.GetNextToken() == "" {
+			break
+		}
+		token = resp.GetNextToken()
+	}
+
+	if len(seen) != 5 {
+		t.Fatalf("expected to see all 5 volumes across pages, got %d: %v", len(seen), seen)
+	}
+
+	// An out-of-range starting token is a valid "no more results" boundary, not an error.
+	outOfRange := base64.StdEncoding.EncodeToString([]byte(strconv.Itoa(len(seen))))
+	last, err := d.ListVolumes(context.Background(), &csi.ListVolumesRequest{MaxEntries: 2, StartingToken: outOfRange})
+	if err != nil {
+		t.Fatalf("expected an out-of-range starting token to succeed, got %v", err)
+	}
+	if len(last.GetEntries()) != 0 {
+		t.Errorf("expected no entries once pagination is exhausted")
+	}
+}
+
+func TestListVolumesDetachedReportsNoPublishedNodes(t *testing.T) {
+	fc := newFakeCloud()
+	d := newTestControllerService(fc)
+	if _, err := fc.CreateDisk(context.Background(), "vol", &cloud.DiskOptions{}); err != nil {
+		t.Fatalf("setup: %v", err)
+	}
+
+	resp, err := d.ListVolumes(context.Background(), &csi.ListVolumesRequest{})
+	if err != nil {
+		t.Fatalf("ListVolumes failed: %v", err)
+	}
+	if len(resp.GetEntries()) != 1 {
+		t.Fatalf("expected exactly one volume, got %d", len(resp.GetEntries()))
+	}
+	if nodes := resp.GetEntries()[0].GetStatus().GetPublishedNodeIds(); len(nodes) != 0 {
+		t.Errorf("expected a detached volume to report no published node IDs, got %v", nodes)
+	}
+}
+
+func TestControllerGetVolumeDetached(t *testing.T) {
+	fc := newFakeCloud()
+	d := newTestControllerService(fc)
+	disk, err := fc.CreateDisk(context.Background(), "vol", &cloud.DiskOptions{})
+	if err != nil {
+		t.Fatalf("setup: %v", err)
+	}
+
+	resp, err := d.ControllerGetVolume(context.Background(), &csi.ControllerGetVolumeRequest{VolumeId: disk.VolumeID})
+	if err != nil {
+		t.Fatalf("ControllerGetVolume failed: %v", err)
+	}
+	if nodes := resp.GetStatus().GetPublishedNodeIds(); len(nodes) != 0 {
+		t.Errorf("expected a detached volume to report no published node IDs, got %v", nodes)
+	}
+}
+
+func TestControllerGetVolumeNotFound(t *testing.T) {
+	d := newTestControllerService(newFakeCloud())
+	_, err := d.ControllerGetVolume(context.Background(), &csi.ControllerGetVolumeRequest{VolumeId: "does-not-exist"})
+	if status.Code(err) != codes.NotFound {
+		t.Errorf("expected codes.NotFound for a missing volume, got %v", err)
+	}
+}
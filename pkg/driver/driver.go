@@ -0,0 +1,106 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package driver
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"strings"
+
+	csi "github.com/container-storage-interface/spec/lib/go/csi"
+	"google.golang.org/grpc"
+	"k8s.io/klog/v2"
+	"k8s.io/mount-utils"
+)
+
+// Driver implements the CSI identity, controller, and node services as a single gRPC server,
+// the way external-provisioner/external-attacher/kubelet expect to reach them on one endpoint.
+type Driver struct {
+	identityService
+	controllerService
+	nodeService
+
+	srv *grpc.Server
+}
+
+// NewDriver wires up a Driver from options. It panics if the controller service fails to
+// reach the PowerVS APIs, matching newControllerService's own convention.
+func NewDriver(options *Options) *Driver {
+	return &Driver{
+		controllerService: newControllerService(options),
+		nodeService:       nodeService{mounter: mount.New("")},
+	}
+}
+
+// Run starts serving the CSI gRPC services on endpoint (a unix:// or tcp:// address) and
+// blocks until the server stops.
+func (d *Driver) Run(endpoint string) error {
+	listener, err := d.prepare(endpoint)
+	if err != nil {
+		return err
+	}
+	klog.V(4).Infof("Driver: serving on %s", endpoint)
+	return d.srv.Serve(listener)
+}
+
+// prepare opens the listener for endpoint and registers the CSI services on d.srv, without
+// blocking on Serve. It exists separately from Run so tests can start serving in a goroutine
+// only once the listener is known to be ready, instead of racing a freshly spawned goroutine.
+func (d *Driver) prepare(endpoint string) (net.Listener, error) {
+	scheme, addr, err := parseEndpoint(endpoint)
+	if err != nil {
+		return nil, err
+	}
+
+	if scheme == "unix" {
+		if err := os.Remove(addr); err != nil && !os.IsNotExist(err) {
+			return nil, fmt.Errorf("failed to remove stale socket %q: %v", addr, err)
+		}
+	}
+
+	listener, err := net.Listen(scheme, addr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to listen on %q: %v", endpoint, err)
+	}
+
+	d.srv = grpc.NewServer()
+	csi.RegisterIdentityServer(d.srv, &d.identityService)
+	csi.RegisterControllerServer(d.srv, &d.controllerService)
+	csi.RegisterNodeServer(d.srv, &d.nodeService)
+
+	return listener, nil
+}
+
+// Stop gracefully stops a running Driver.
+func (d *Driver) Stop() {
+	if d.srv != nil {
+		d.srv.GracefulStop()
+	}
+}
+
+func parseEndpoint(endpoint string) (string, string, error) {
+	scheme, addr, ok := strings.Cut(endpoint, "://")
+	if !ok {
+		return "", "", fmt.Errorf("invalid endpoint %q: expected scheme://addr", endpoint)
+	}
+	scheme = strings.ToLower(scheme)
+	if scheme != "unix" && scheme != "tcp" {
+		return "", "", fmt.Errorf("unsupported endpoint scheme %q", scheme)
+	}
+	return scheme, addr, nil
+}
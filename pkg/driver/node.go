@@ -0,0 +1,163 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package driver
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+
+	csi "github.com/container-storage-interface/spec/lib/go/csi"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"k8s.io/klog/v2"
+	"k8s.io/mount-utils"
+)
+
+// nodeService represents the node service of CSI driver
+type nodeService struct {
+	mounter mount.Interface
+}
+
+// findDevicePath resolves the WWN supplied in PublishContext to the device node for it,
+// preferring the multipath alias (/dev/mapper/mpathX, reached via its dm-uuid by-id symlink)
+// over the single-path /dev/disk/by-id/wwn-<wwn> link, since hosts with multipathd running
+// only ever populate the former.
+func findDevicePath(wwn string) (string, error) {
+	candidates := []string{
+		"/dev/disk/by-id/dm-uuid-mpath-0x" + wwn,
+		"/dev/disk/by-id/wwn-0x" + wwn,
+	}
+	for _, candidate := range candidates {
+		if resolved, err := filepath.EvalSymlinks(candidate); err == nil {
+			return resolved, nil
+		}
+	}
+	return "", status.Errorf(codes.Internal, "could not find device path for WWN %q", wwn)
+}
+
+// nodePublishVolumeForBlock bind-mounts the raw multipath device node for volumeID directly
+// onto targetPath, skipping mkfs/mount entirely.
+func (d *nodeService) nodePublishVolumeForBlock(req *csi.NodePublishVolumeRequest, mountOptions []string) error {
+	target := req.GetTargetPath()
+	volumeID := req.GetVolumeId()
+	wwn := req.GetPublishContext()[WWNKey]
+
+	notMnt, err := d.mounter.IsLikelyNotMountPoint(target)
+	if err != nil && !os.IsNotExist(err) {
+		return status.Errorf(codes.Internal, "Could not determine mount state of target %q: %v", target, err)
+	}
+	if err == nil && !notMnt {
+		klog.V(4).Infof("NodePublishVolume: target %q is already mounted for volume %q, returning success", target, volumeID)
+		return nil
+	}
+
+	devicePath, err := findDevicePath(wwn)
+	if err != nil {
+		return err
+	}
+
+	if err := d.mounter.MakeFile(target); err != nil {
+		if !os.IsExist(err) {
+			return status.Errorf(codes.Internal, "Could not create target file %q for volume %q: %v", target, volumeID, err)
+		}
+	}
+
+	if err := d.mounter.Mount(devicePath, target, "", mountOptions); err != nil {
+		if removeErr := os.Remove(target); removeErr != nil {
+			return status.Errorf(codes.Internal, "Could not bind mount %q at %q: %v (cleanup also failed: %v)", devicePath, target, err, removeErr)
+		}
+		return status.Errorf(codes.Internal, "Could not bind mount %q at %q: %v", devicePath, target, err)
+	}
+
+	return nil
+}
+
+func (d *nodeService) NodePublishVolume(ctx context.Context, req *csi.NodePublishVolumeRequest) (*csi.NodePublishVolumeResponse, error) {
+	klog.V(4).Infof("NodePublishVolume: called with args %+v", *req)
+	volumeID := req.GetVolumeId()
+	if len(volumeID) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "Volume ID not provided")
+	}
+
+	target := req.GetTargetPath()
+	if len(target) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "Target path not provided")
+	}
+
+	volCap := req.GetVolumeCapability()
+	if volCap == nil {
+		return nil, status.Error(codes.InvalidArgument, "Volume capability not provided")
+	}
+
+	if mode := req.GetPublishContext()[VolumeModeKey]; mode != "" {
+		isBlock := volCap.GetBlock() != nil
+		if (mode == VolumeModeBlock) != isBlock {
+			return nil, status.Errorf(codes.InvalidArgument, "Volume %q was provisioned as %q but NodePublishVolume was called with a mismatched volume capability", volumeID, mode)
+		}
+	}
+
+	mountOptions := []string{"bind"}
+	if m := volCap.GetMount(); m != nil {
+		mountOptions = append(mountOptions, m.GetMountFlags()...)
+	}
+
+	if volCap.GetBlock() != nil {
+		if err := d.nodePublishVolumeForBlock(req, mountOptions); err != nil {
+			return nil, err
+		}
+		return &csi.NodePublishVolumeResponse{}, nil
+	}
+
+	source := req.GetStagingTargetPath()
+	if len(source) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "Staging target path not provided")
+	}
+
+	notMnt, err := d.mounter.IsLikelyNotMountPoint(target)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, status.Errorf(codes.Internal, "Could not determine mount state of target %q: %v", target, err)
+	}
+	if err == nil && !notMnt {
+		klog.V(4).Infof("NodePublishVolume: target %q is already mounted for volume %q, returning success", target, volumeID)
+		return &csi.NodePublishVolumeResponse{}, nil
+	}
+
+	if err := d.mounter.MakeDir(target); err != nil {
+		return nil, status.Errorf(codes.Internal, "Could not create target dir %q: %v", target, err)
+	}
+
+	if err := d.mounter.Mount(source, target, "", mountOptions); err != nil {
+		return nil, status.Errorf(codes.Internal, "Could not mount %q at %q: %v", source, target, err)
+	}
+
+	return &csi.NodePublishVolumeResponse{}, nil
+}
+
+func (d *nodeService) NodeUnpublishVolume(ctx context.Context, req *csi.NodeUnpublishVolumeRequest) (*csi.NodeUnpublishVolumeResponse, error) {
+	klog.V(4).Infof("NodeUnpublishVolume: called with args %+v", *req)
+	target := req.GetTargetPath()
+	if len(target) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "Target path not provided")
+	}
+
+	if err := mount.CleanupMountPoint(target, d.mounter, true); err != nil {
+		return nil, status.Errorf(codes.Internal, "Could not unmount target %q: %v", target, err)
+	}
+
+	return &csi.NodeUnpublishVolumeResponse{}, nil
+}
@@ -0,0 +1,61 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package driver
+
+import (
+	"flag"
+	"fmt"
+
+	"github.com/ppc64le-cloud/powervs-csi-driver/pkg/cloud"
+)
+
+// Options holds the driver's command line configuration
+type Options struct {
+	debug bool
+	// metadataSource forces NewMetadataService down a single discovery path, mainly for
+	// sanity/e2e test rigs that don't run against a real Kubernetes cluster.
+	metadataSource cloud.MetadataSource
+}
+
+// metadataSourceFlag adapts cloud.MetadataSource to flag.Value so --metadata-source can be
+// validated against the supported values at parse time instead of at NewMetadataService call time.
+type metadataSourceFlag struct {
+	source *cloud.MetadataSource
+}
+
+func (f *metadataSourceFlag) String() string {
+	if f.source == nil {
+		return ""
+	}
+	return string(*f.source)
+}
+
+func (f *metadataSourceFlag) Set(value string) error {
+	switch cloud.MetadataSource(value) {
+	case cloud.MetadataSourceAuto, cloud.MetadataSourceKubernetes, cloud.MetadataSourceLocal:
+		*f.source = cloud.MetadataSource(value)
+		return nil
+	default:
+		return fmt.Errorf("invalid --metadata-source %q: must be one of auto, kubernetes, local", value)
+	}
+}
+
+// AddFlags registers the driver's command line flags on fs.
+func (o *Options) AddFlags(fs *flag.FlagSet) {
+	fs.BoolVar(&o.debug, "debug", false, "Enable debug logging of PowerVS API calls")
+	fs.Var(&metadataSourceFlag{source: &o.metadataSource}, "metadata-source", "The source NewMetadataService discovers instance identity from: auto, kubernetes, or local. Defaults to auto, which tries kubernetes then falls back to local.")
+}
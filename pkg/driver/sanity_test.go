@@ -0,0 +1,65 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package driver
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/kubernetes-csi/csi-test/v5/pkg/sanity"
+	"k8s.io/mount-utils"
+)
+
+// TestSanity runs the csi-test sanity suite, including its VolumeCapability_Block coverage,
+// against a Driver backed by a fakeCloud and a FakeMounter over a real unix-socket gRPC
+// endpoint - the same driver wiring a CO would talk to, rather than calling controllerService/
+// nodeService methods directly.
+func TestSanity(t *testing.T) {
+	d := &Driver{
+		controllerService: *newTestControllerService(newFakeCloud()),
+		nodeService:       nodeService{mounter: mount.NewFakeMounter(nil)},
+	}
+
+	sockPath := filepath.Join(t.TempDir(), "csi.sock")
+	endpoint := "unix://" + sockPath
+
+	listener, err := d.prepare(endpoint)
+	if err != nil {
+		t.Fatalf("failed to start driver: %v", err)
+	}
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- d.srv.Serve(listener)
+	}()
+	defer d.Stop()
+
+	tmpDir := t.TempDir()
+	config := sanity.NewTestConfig()
+	config.Address = endpoint
+	config.TargetPath = filepath.Join(tmpDir, "target")
+	config.StagingPath = filepath.Join(tmpDir, "staging")
+
+	sanity.Test(t, config)
+
+	select {
+	case err := <-errCh:
+		if err != nil {
+			t.Fatalf("driver stopped unexpectedly: %v", err)
+		}
+	default:
+	}
+}
@@ -0,0 +1,35 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import csi "github.com/container-storage-interface/spec/lib/go/csi"
+
+// HasMultiNodeAccessMode returns true if any of the given capabilities requests a
+// multi-node access mode, implying the caller wants a shareable volume. Kept in sync with
+// the access modes PowerVS shareable volumes actually support (see shareableVolumeCaps in
+// the driver package) - MULTI_NODE_SINGLE_WRITER is intentionally excluded since PowerVS has
+// no way to enforce single-writer semantics across multiple attached nodes.
+func HasMultiNodeAccessMode(volCaps []*csi.VolumeCapability) bool {
+	for _, c := range volCaps {
+		switch c.GetAccessMode().GetMode() {
+		case csi.VolumeCapability_AccessMode_MULTI_NODE_MULTI_WRITER,
+			csi.VolumeCapability_AccessMode_MULTI_NODE_READER_ONLY:
+			return true
+		}
+	}
+	return false
+}
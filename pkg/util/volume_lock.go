@@ -0,0 +1,42 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import "sync"
+
+// VolumeLocks prevents concurrent operations against the same volume (or node) ID,
+// following the same pattern as ceph-csi's util.VolumeLocks.
+type VolumeLocks struct {
+	locks sync.Map
+}
+
+// NewVolumeLocks returns a ready to use VolumeLocks
+func NewVolumeLocks() *VolumeLocks {
+	return &VolumeLocks{}
+}
+
+// TryAcquire acquires a lock for the given id if it is not already held, and returns
+// true on success. The caller must call Release(id) once done.
+func (vl *VolumeLocks) TryAcquire(id string) bool {
+	_, ok := vl.locks.LoadOrStore(id, struct{}{})
+	return !ok
+}
+
+// Release releases a lock previously acquired with TryAcquire.
+func (vl *VolumeLocks) Release(id string) {
+	vl.locks.Delete(id)
+}
@@ -0,0 +1,63 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import "testing"
+
+func TestVolumeLocksTryAcquireRelease(t *testing.T) {
+	vl := NewVolumeLocks()
+
+	if !vl.TryAcquire("vol-1") {
+		t.Fatal("expected first TryAcquire to succeed")
+	}
+	if vl.TryAcquire("vol-1") {
+		t.Fatal("expected second TryAcquire for the same id to fail while the lock is held")
+	}
+	if !vl.TryAcquire("vol-2") {
+		t.Fatal("expected TryAcquire for a different id to succeed")
+	}
+
+	vl.Release("vol-1")
+	if !vl.TryAcquire("vol-1") {
+		t.Fatal("expected TryAcquire to succeed again after Release")
+	}
+}
+
+func TestVolumeLocksConcurrentAcquire(t *testing.T) {
+	vl := NewVolumeLocks()
+	const attempts = 50
+
+	successCh := make(chan bool, attempts)
+	start := make(chan struct{})
+	for i := 0; i < attempts; i++ {
+		go func() {
+			<-start
+			successCh <- vl.TryAcquire("vol-1")
+		}()
+	}
+	close(start)
+
+	successes := 0
+	for i := 0; i < attempts; i++ {
+		if <-successCh {
+			successes++
+		}
+	}
+	if successes != 1 {
+		t.Errorf("expected exactly one concurrent TryAcquire to succeed, got %d", successes)
+	}
+}